@@ -0,0 +1,162 @@
+// Package auth validates bearer tokens for the admin API and exposes the
+// claims they carry so handlers and the RBAC middleware can act on them.
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the custom JWT claims the admin API expects, in addition to
+// the standard registered claims (exp, iat, sub, ...).
+type Claims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// Validator validates bearer tokens signed with either a shared HS256
+// secret or an RS256 key published via a JWKS endpoint.
+type Validator struct {
+	hs256Secret []byte
+
+	jwksURL string
+	jwks    *jwksCache
+}
+
+// NewValidator builds a Validator. hs256Secret may be empty if only RS256
+// tokens are expected; jwksURL may be empty if only HS256 tokens are
+// expected. At least one should be set for the validator to accept anything.
+func NewValidator(hs256Secret, jwksURL string) *Validator {
+	v := &Validator{jwksURL: jwksURL}
+	if hs256Secret != "" {
+		v.hs256Secret = []byte(hs256Secret)
+	}
+	if jwksURL != "" {
+		v.jwks = newJWKSCache(jwksURL, 10*time.Minute)
+	}
+	return v
+}
+
+// ParseToken validates tokenString's signature and expiry and returns its
+// claims.
+func (v *Validator) ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.Alg() {
+		case "HS256":
+			if v.hs256Secret == nil {
+				return nil, fmt.Errorf("HS256 tokens are not accepted")
+			}
+			return v.hs256Secret, nil
+		case "RS256":
+			if v.jwks == nil {
+				return nil, fmt.Errorf("RS256 tokens are not accepted")
+			}
+			kid, _ := t.Header["kid"].(string)
+			return v.jwks.key(kid)
+		default:
+			return nil, fmt.Errorf("unsupported signing method %q", t.Method.Alg())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// jwksCache fetches and caches the RSA public keys published at a JWKS
+// endpoint, refreshing them at most once per ttl.
+type jwksCache struct {
+	url string
+	ttl time.Duration
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+	client  *http.Client
+}
+
+func newJWKSCache(url string, ttl time.Duration) *jwksCache {
+	return &jwksCache{url: url, ttl: ttl, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type jwkSet struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (c *jwksCache) key(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil || time.Since(c.fetched) > c.ttl {
+		if err := c.refreshLocked(); err != nil {
+			return nil, err
+		}
+	}
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no JWKS key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+func (c *jwksCache) refreshLocked() error {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching JWKS: unexpected status %s", resp.Status)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decoding JWKS: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := rsaPublicKeyFromJWK(k.N, k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	c.keys = keys
+	c.fetched = time.Now()
+	return nil
+}
+
+func rsaPublicKeyFromJWK(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("decoding modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("decoding exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signedToken(t *testing.T, method jwt.SigningMethod, key interface{}, roles []string) string {
+	t.Helper()
+	claims := &Claims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed
+}
+
+func TestParseTokenHS256(t *testing.T) {
+	v := NewValidator("test-secret", "")
+
+	token := signedToken(t, jwt.SigningMethodHS256, []byte("test-secret"), []string{"admin"})
+	claims, err := v.ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	if claims.Subject != "user-1" || len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestParseTokenRejectsNoneAlgorithm(t *testing.T) {
+	v := NewValidator("test-secret", "")
+
+	claims := &Claims{
+		Roles: []string{"admin"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "attacker",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("signing none-alg token: %v", err)
+	}
+
+	if _, err := v.ParseToken(token); err == nil {
+		t.Error("expected ParseToken to reject an alg=none token, got nil error")
+	}
+}
+
+func TestParseTokenRejectsHS256WhenNotConfigured(t *testing.T) {
+	// Validator only accepts RS256 (via JWKS); no HS256 secret configured.
+	v := NewValidator("", "https://example.invalid/jwks.json")
+
+	token := signedToken(t, jwt.SigningMethodHS256, []byte("whatever-secret-an-attacker-picks"), []string{"admin"})
+	if _, err := v.ParseToken(token); err == nil {
+		t.Error("expected ParseToken to reject an HS256 token when only RS256 is configured, got nil error")
+	}
+}
+
+func TestParseTokenRejectsRS256WhenNotConfigured(t *testing.T) {
+	// Validator only accepts HS256; no JWKS URL configured.
+	v := NewValidator("test-secret", "")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	token := signedToken(t, jwt.SigningMethodRS256, key, []string{"admin"})
+
+	if _, err := v.ParseToken(token); err == nil {
+		t.Error("expected ParseToken to reject an RS256 token when only HS256 is configured, got nil error")
+	}
+}
+
+func TestParseTokenRejectsUnsupportedAlgorithm(t *testing.T) {
+	v := NewValidator("test-secret", "https://example.invalid/jwks.json")
+
+	token := signedToken(t, jwt.SigningMethodHS384, []byte("test-secret"), []string{"admin"})
+	if _, err := v.ParseToken(token); err == nil {
+		t.Error("expected ParseToken to reject an unsupported signing method, got nil error")
+	}
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/policy"
+)
+
+type contextKey string
+
+const claimsContextKey contextKey = "auth.claims"
+
+// ClaimsFromContext returns the claims attached by Middleware, if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey).(*Claims)
+	return claims, ok
+}
+
+// Problem is an RFC 7807 problem+json body.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, status int, title, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:   "about:blank",
+		Title:  title,
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// DecisionLogger is called with the policy decision ID after every
+// authorization check, for audit logging.
+type DecisionLogger func(decisionID string, claims *Claims, allowed bool, r *http.Request)
+
+// Middleware validates the bearer token on every request with validator,
+// then asks engine's "data.admin.allow" rule whether the authenticated user
+// may perform this request. On success, the parsed claims are attached to
+// the request context.
+func Middleware(validator *Validator, engine *policy.Engine, logDecision DecisionLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				writeProblem(w, http.StatusUnauthorized, "missing bearer token", "")
+				return
+			}
+			token := strings.TrimPrefix(header, "Bearer ")
+
+			claims, err := validator.ParseToken(token)
+			if err != nil {
+				writeProblem(w, http.StatusUnauthorized, "invalid token", err.Error())
+				return
+			}
+
+			input := map[string]interface{}{
+				"user":     claims.Subject,
+				"roles":    claims.Roles,
+				"action":   r.Method,
+				"resource": r.URL.Path,
+				"method":   r.Method,
+				"path":     r.URL.Path,
+			}
+
+			result, err := engine.Evaluate(r.Context(), "data.admin.allow", input, nil)
+			if err != nil {
+				writeProblem(w, http.StatusForbidden, "authorization check failed", err.Error())
+				return
+			}
+			if logDecision != nil {
+				logDecision(result.DecisionID, claims, result.Result, r)
+			}
+			if !result.Result {
+				writeProblem(w, http.StatusForbidden, "not authorized", "")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package cache provides a small Redis-backed cache used to avoid repeating
+// expensive geo lookups for hot postcodes and coordinates.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Cache wraps a Redis client. A nil underlying client is treated as a
+// no-op cache so callers can run without Redis configured (Get always
+// misses, Set always succeeds silently).
+type Cache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// New returns a Cache backed by addr ("host:port"). If addr is empty, the
+// returned Cache is a no-op.
+func New(addr string, ttl time.Duration) *Cache {
+	if addr == "" {
+		return &Cache{ttl: ttl}
+	}
+	return &Cache{
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+		ttl:    ttl,
+	}
+}
+
+// Ping verifies connectivity, returning nil immediately for a no-op cache.
+func (c *Cache) Ping(ctx context.Context) error {
+	if c.client == nil {
+		return nil
+	}
+	return c.client.Ping(ctx).Err()
+}
+
+// Get unmarshals the cached value for key into dest, returning false on a
+// miss (including when the cache is a no-op or Redis is unreachable).
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) bool {
+	if c.client == nil {
+		return false
+	}
+	raw, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(raw, dest) == nil
+}
+
+// Set marshals value as JSON and stores it under key with the cache's TTL.
+// Errors are swallowed: a failed cache write should never fail the request
+// it's caching for.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}) {
+	if c.client == nil {
+		return
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.client.Set(ctx, key, raw, c.ttl)
+}
@@ -0,0 +1,107 @@
+// Package geo provides point-in-polygon boundary lookups and postcode
+// centroid resolution for the Represent Canada endpoints.
+package geo
+
+import "math"
+
+// Point is a WGS84 coordinate pair.
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Ring is a closed sequence of points describing a polygon boundary. The
+// first ring of a Polygon is the exterior; any further rings are holes.
+type Ring []Point
+
+// Polygon is an exterior ring plus zero or more interior holes, matching the
+// GeoJSON Polygon coordinate layout.
+type Polygon []Ring
+
+// Boundary is an electoral or administrative boundary at a given level
+// (federal, provincial, municipal) together with its geometry.
+type Boundary struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Level      string    `json:"level"`
+	Province   string    `json:"province"`
+	Population int       `json:"population,omitempty"`
+	AreaKM2    float64   `json:"area_km2,omitempty"`
+	Polygons   []Polygon `json:"-"`
+}
+
+// earthRadiusKM is the mean radius used for the haversine approximation.
+const earthRadiusKM = 6371.0088
+
+// DistanceKM returns the great-circle distance between a and b in kilometres.
+func DistanceKM(a, b Point) float64 {
+	lat1, lat2 := deg2rad(a.Lat), deg2rad(b.Lat)
+	dLat := deg2rad(b.Lat - a.Lat)
+	dLng := deg2rad(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	c := 2 * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+	return earthRadiusKM * c
+}
+
+func deg2rad(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+// Contains reports whether pt falls inside the polygon, honouring holes: a
+// point inside the exterior ring but also inside a hole ring is excluded.
+func (p Polygon) Contains(pt Point) bool {
+	if len(p) == 0 || !ringContains(p[0], pt) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the axis-aligned bounding box (min, max) of all the
+// polygon's rings, used to build R-tree entries.
+func (p Polygon) Bounds() (min, max [2]float64) {
+	if len(p) == 0 || len(p[0]) == 0 {
+		return
+	}
+	min = [2]float64{p[0][0].Lng, p[0][0].Lat}
+	max = min
+	for _, ring := range p {
+		for _, pt := range ring {
+			if pt.Lng < min[0] {
+				min[0] = pt.Lng
+			}
+			if pt.Lat < min[1] {
+				min[1] = pt.Lat
+			}
+			if pt.Lng > max[0] {
+				max[0] = pt.Lng
+			}
+			if pt.Lat > max[1] {
+				max[1] = pt.Lat
+			}
+		}
+	}
+	return
+}
+
+// ringContains implements the standard even-odd ray casting test.
+func ringContains(ring Ring, pt Point) bool {
+	inside := false
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		pi, pj := ring[i], ring[j]
+		if (pi.Lat > pt.Lat) != (pj.Lat > pt.Lat) {
+			x := (pj.Lng-pi.Lng)*(pt.Lat-pi.Lat)/(pj.Lat-pi.Lat) + pi.Lng
+			if pt.Lng < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func square(minLng, minLat, maxLng, maxLat float64) Ring {
+	return Ring{
+		{Lat: minLat, Lng: minLng},
+		{Lat: minLat, Lng: maxLng},
+		{Lat: maxLat, Lng: maxLng},
+		{Lat: maxLat, Lng: minLng},
+		{Lat: minLat, Lng: minLng},
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	poly := Polygon{square(-1, -1, 1, 1)}
+
+	tests := []struct {
+		name string
+		pt   Point
+		want bool
+	}{
+		{"center", Point{Lat: 0, Lng: 0}, true},
+		{"well outside", Point{Lat: 5, Lng: 5}, false},
+		{"just inside corner", Point{Lat: 0.99, Lng: 0.99}, true},
+		{"just outside corner", Point{Lat: 1.01, Lng: 1.01}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := poly.Contains(tt.pt); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolygonContainsHole(t *testing.T) {
+	// Outer 4x4 square with a 2x2 hole in the middle.
+	poly := Polygon{
+		square(-2, -2, 2, 2),
+		square(-1, -1, 1, 1),
+	}
+
+	if !poly.Contains(Point{Lat: 1.5, Lng: 1.5}) {
+		t.Error("expected point between the outer ring and the hole to be contained")
+	}
+	if poly.Contains(Point{Lat: 0, Lng: 0}) {
+		t.Error("expected point inside the hole to be excluded")
+	}
+	if poly.Contains(Point{Lat: 3, Lng: 3}) {
+		t.Error("expected point outside the outer ring to be excluded")
+	}
+}
+
+func TestIndexPointLookupMultiPolygon(t *testing.T) {
+	idx := NewIndex()
+	b := &Boundary{
+		ID:    "B-multi",
+		Level: "federal",
+		Polygons: []Polygon{
+			{square(-10, -10, -9, -9)},
+			{square(9, 9, 10, 10)},
+		},
+	}
+	idx.Insert(b)
+
+	for _, pt := range []Point{{Lat: -9.5, Lng: -9.5}, {Lat: 9.5, Lng: 9.5}} {
+		hits := idx.PointLookup(pt.Lat, pt.Lng)
+		if len(hits) != 1 || hits[0].ID != "B-multi" {
+			t.Errorf("PointLookup(%v) = %v, want a single hit on B-multi", pt, hits)
+		}
+	}
+
+	if hits := idx.PointLookup(0, 0); len(hits) != 0 {
+		t.Errorf("PointLookup(0,0) = %v, want no hits between the two disjoint parts", hits)
+	}
+}
+
+func TestDistanceKM(t *testing.T) {
+	// Toronto to Ottawa is roughly 350km.
+	toronto := Point{Lat: 43.6532, Lng: -79.3832}
+	ottawa := Point{Lat: 45.4215, Lng: -75.6972}
+
+	got := DistanceKM(toronto, ottawa)
+	if math.Abs(got-351) > 15 {
+		t.Errorf("DistanceKM(Toronto, Ottawa) = %.1f, want ~351km", got)
+	}
+
+	if d := DistanceKM(toronto, toronto); d != 0 {
+		t.Errorf("DistanceKM(p, p) = %v, want 0", d)
+	}
+}
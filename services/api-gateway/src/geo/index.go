@@ -0,0 +1,191 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/tidwall/rtree"
+)
+
+// entry is what gets stored in the R-tree so a bounding-box hit can be
+// resolved back to the boundary and the specific polygon ring that produced
+// the match (a boundary can be a MultiPolygon).
+type entry struct {
+	boundary *Boundary
+	polygon  Polygon
+}
+
+// Index is an in-memory spatial index over boundary polygons at every level
+// (federal, provincial, municipal). Bounding-box candidates from the R-tree
+// are confirmed with an exact point-in-polygon test before being returned.
+type Index struct {
+	mu   sync.RWMutex
+	tree rtree.RTree
+	byID map[string]*Boundary
+}
+
+// NewIndex returns an empty, ready-to-use Index.
+func NewIndex() *Index {
+	return &Index{byID: map[string]*Boundary{}}
+}
+
+// Insert adds a boundary's polygons to the index.
+func (idx *Index) Insert(b *Boundary) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.byID[b.ID] = b
+	for _, poly := range b.Polygons {
+		min, max := poly.Bounds()
+		idx.tree.Insert(min, max, entry{boundary: b, polygon: poly})
+	}
+}
+
+// Len returns the number of distinct boundaries loaded.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.byID)
+}
+
+// PointLookup returns every boundary, across all levels, whose polygon
+// contains (lat, lng).
+func (idx *Index) PointLookup(lat, lng float64) []*Boundary {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	seen := map[string]bool{}
+	var hits []*Boundary
+	pt := Point{Lat: lat, Lng: lng}
+
+	box := [2]float64{lng, lat}
+	idx.tree.Search(box, box, func(min, max [2]float64, data interface{}) bool {
+		e := data.(entry)
+		if seen[e.boundary.ID] {
+			return true
+		}
+		if e.polygon.Contains(pt) {
+			seen[e.boundary.ID] = true
+			hits = append(hits, e.boundary)
+		}
+		return true
+	})
+	return hits
+}
+
+// All returns every boundary currently loaded into the index.
+func (idx *Index) All() []*Boundary {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	out := make([]*Boundary, 0, len(idx.byID))
+	for _, b := range idx.byID {
+		out = append(out, b)
+	}
+	return out
+}
+
+// Boundary returns a previously loaded boundary by ID.
+func (idx *Index) Boundary(id string) (*Boundary, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	b, ok := idx.byID[id]
+	return b, ok
+}
+
+// geoJSONFeatureCollection is the subset of GeoJSON this loader understands:
+// Polygon and MultiPolygon features carrying boundary metadata as properties.
+type geoJSONFeatureCollection struct {
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// LoadGeoJSONFile parses a GeoJSON FeatureCollection of boundary polygons at
+// the given level (e.g. "federal", "provincial", "municipal") and inserts
+// each feature into the index.
+func (idx *Index) LoadGeoJSONFile(path, level string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return idx.LoadGeoJSON(data, level)
+}
+
+// LoadGeoJSON parses raw GeoJSON bytes, as LoadGeoJSONFile does.
+func (idx *Index) LoadGeoJSON(data []byte, level string) error {
+	var fc geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("parsing GeoJSON: %w", err)
+	}
+
+	for _, f := range fc.Features {
+		polys, err := parseGeometry(f.Geometry.Type, f.Geometry.Coordinates)
+		if err != nil {
+			return fmt.Errorf("feature %v: %w", f.Properties["id"], err)
+		}
+		b := &Boundary{
+			Level:    level,
+			Polygons: polys,
+		}
+		if v, ok := f.Properties["id"].(string); ok {
+			b.ID = v
+		}
+		if v, ok := f.Properties["name"].(string); ok {
+			b.Name = v
+		}
+		if v, ok := f.Properties["province"].(string); ok {
+			b.Province = v
+		}
+		if v, ok := f.Properties["population"].(float64); ok {
+			b.Population = int(v)
+		}
+		if v, ok := f.Properties["area_km2"].(float64); ok {
+			b.AreaKM2 = v
+		}
+		idx.Insert(b)
+	}
+	return nil
+}
+
+func parseGeometry(geomType string, coords json.RawMessage) ([]Polygon, error) {
+	switch geomType {
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(coords, &rings); err != nil {
+			return nil, err
+		}
+		return []Polygon{toPolygon(rings)}, nil
+	case "MultiPolygon":
+		var multi [][][][2]float64
+		if err := json.Unmarshal(coords, &multi); err != nil {
+			return nil, err
+		}
+		polys := make([]Polygon, 0, len(multi))
+		for _, rings := range multi {
+			polys = append(polys, toPolygon(rings))
+		}
+		return polys, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", geomType)
+	}
+}
+
+func toPolygon(rings [][][2]float64) Polygon {
+	poly := make(Polygon, len(rings))
+	for i, ring := range rings {
+		r := make(Ring, len(ring))
+		for j, c := range ring {
+			r[j] = Point{Lng: c[0], Lat: c[1]}
+		}
+		poly[i] = r
+	}
+	return poly
+}
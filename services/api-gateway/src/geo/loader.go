@@ -0,0 +1,103 @@
+package geo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// LoaderConfig points at the upstream sources the gateway pulls boundary and
+// postcode data from on startup. Each field may be a local filesystem path
+// or an http(s) URL; empty fields are skipped.
+type LoaderConfig struct {
+	FederalBoundariesURL    string
+	ProvincialBoundariesURL string
+	MunicipalBoundariesURL  string
+	PostcodeCentroidsURL    string
+}
+
+// Loader pulls Statistics Canada boundary files and Canada Post FSA
+// centroids into an Index and PostcodeIndex.
+type Loader struct {
+	Config LoaderConfig
+	Client *http.Client
+}
+
+// NewLoader returns a Loader with a bounded HTTP client suitable for
+// fetching boundary files at startup.
+func NewLoader(cfg LoaderConfig) *Loader {
+	return &Loader{
+		Config: cfg,
+		Client: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Load fetches every configured source and populates boundaries and
+// postcodes. It continues past individual source failures, returning a
+// combined error so callers can log-and-continue with partial data rather
+// than failing to start.
+func (l *Loader) Load(boundaries *Index, postcodes *PostcodeIndex) error {
+	var errs []error
+
+	for level, url := range map[string]string{
+		"federal":    l.Config.FederalBoundariesURL,
+		"provincial": l.Config.ProvincialBoundariesURL,
+		"municipal":  l.Config.MunicipalBoundariesURL,
+	} {
+		if url == "" {
+			continue
+		}
+		data, err := l.fetch(url)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s boundaries: %w", level, err))
+			continue
+		}
+		if err := boundaries.LoadGeoJSON(data, level); err != nil {
+			errs = append(errs, fmt.Errorf("%s boundaries: %w", level, err))
+		}
+	}
+
+	if l.Config.PostcodeCentroidsURL != "" {
+		data, err := l.fetch(l.Config.PostcodeCentroidsURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("postcode centroids: %w", err))
+		} else if err := postcodes.LoadCSV(newByteReader(data)); err != nil {
+			errs = append(errs, fmt.Errorf("postcode centroids: %w", err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("geo loader: %d source(s) failed: %v", len(errs), errs)
+}
+
+func (l *Loader) fetch(location string) ([]byte, error) {
+	if isURL(location) {
+		resp, err := l.Client.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+	return readFile(location)
+}
+
+func isURL(s string) bool {
+	return len(s) > 7 && (s[:7] == "http://" || (len(s) > 8 && s[:8] == "https://"))
+}
+
+func readFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func newByteReader(data []byte) *bytes.Reader {
+	return bytes.NewReader(data)
+}
@@ -0,0 +1,103 @@
+package geo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PostcodeIndex resolves a Canadian postcode (or its FSA, the first three
+// characters) to a centroid coordinate.
+type PostcodeIndex struct {
+	mu        sync.RWMutex
+	centroids map[string]Point
+}
+
+// NewPostcodeIndex returns an empty, ready-to-use PostcodeIndex.
+func NewPostcodeIndex() *PostcodeIndex {
+	return &PostcodeIndex{centroids: map[string]Point{}}
+}
+
+// Lookup resolves pc to a centroid. Full six-character postcodes are
+// normalized down to their FSA (first three characters) since that is the
+// granularity Canada Post publishes centroids at.
+func (p *PostcodeIndex) Lookup(pc string) (Point, bool) {
+	fsa := normalizeFSA(pc)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	pt, ok := p.centroids[fsa]
+	return pt, ok
+}
+
+// Len returns the number of FSA centroids loaded.
+func (p *PostcodeIndex) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.centroids)
+}
+
+func normalizeFSA(pc string) string {
+	pc = strings.ToUpper(strings.ReplaceAll(pc, " ", ""))
+	if len(pc) > 3 {
+		pc = pc[:3]
+	}
+	return pc
+}
+
+// LoadCSVFile loads FSA centroids from a CSV file with header
+// "fsa,latitude,longitude".
+func (p *PostcodeIndex) LoadCSVFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	return p.LoadCSV(f)
+}
+
+// LoadCSV loads FSA centroids from an open CSV reader, as LoadCSVFile does.
+func (p *PostcodeIndex) LoadCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	fsaCol, latCol, lngCol := col["fsa"], col["latitude"], col["longitude"]
+
+	loaded := map[string]Point{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading CSV row: %w", err)
+		}
+		lat, err := strconv.ParseFloat(row[latCol], 64)
+		if err != nil {
+			continue
+		}
+		lng, err := strconv.ParseFloat(row[lngCol], 64)
+		if err != nil {
+			continue
+		}
+		loaded[normalizeFSA(row[fsaCol])] = Point{Lat: lat, Lng: lng}
+	}
+
+	p.mu.Lock()
+	for k, v := range loaded {
+		p.centroids[k] = v
+	}
+	p.mu.Unlock()
+	return nil
+}
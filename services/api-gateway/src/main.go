@@ -3,25 +3,46 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/open-policy-agent/opa/rego"
-	"github.com/sirupsen/logrus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/auth"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/cache"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/geo"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/metrics"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/policy"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/scheduler"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/users"
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/wshub"
 )
 
 type PolicyRequest struct {
-	Input map[string]interface{} `json:"input"`
+	Query    string                 `json:"query"`
+	Input    map[string]interface{} `json:"input"`
+	Unknowns []string               `json:"unknowns,omitempty"`
 }
 
 type PolicyResponse struct {
-	Result bool                   `json:"result"`
-	Data   map[string]interface{} `json:"data,omitempty"`
-	Error  string                 `json:"error,omitempty"`
+	Result     bool                     `json:"result"`
+	Results    []map[string]interface{} `json:"results,omitempty"`
+	Queries    []string                 `json:"queries,omitempty"`
+	DecisionID string                   `json:"decision_id,omitempty"`
+	Data       map[string]interface{}   `json:"data,omitempty"`
+	Error      string                   `json:"error,omitempty"`
 }
 
 type HealthResponse struct {
@@ -31,9 +52,11 @@ type HealthResponse struct {
 }
 
 type ScraperRequest struct {
-	Jurisdiction string            `json:"jurisdiction"`
-	Type         string            `json:"type"`
-	Parameters   map[string]string `json:"parameters"`
+	Jurisdiction   string            `json:"jurisdiction"`
+	Type           string            `json:"type"`
+	Parameters     map[string]string `json:"parameters"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+	CronSchedule   string            `json:"cron_schedule,omitempty"`
 }
 
 type ScraperResponse struct {
@@ -54,20 +77,249 @@ type RepresentativeResponse struct {
 	Error           string                   `json:"error,omitempty"`
 }
 
+// VoteRequest records a single roll-call result, broadcast to /ws/votes
+// subscribers as it's recorded.
+type VoteRequest struct {
+	BillID   string `json:"bill_id"`
+	VoteDate string `json:"vote_date"`
+	Result   string `json:"result"`
+	Yea      int    `json:"yea"`
+	Nay      int    `json:"nay"`
+	Abstain  int    `json:"abstain"`
+}
+
 var logger = logrus.New()
 
+var policyEngine *policy.Engine
+
+var (
+	boundaryIndex   *geo.Index
+	postcodeIndex   *geo.PostcodeIndex
+	geoCache        *cache.Cache
+	representatives map[string][]map[string]interface{}
+
+	authValidator *auth.Validator
+	userStore     *users.Store
+
+	wsHub *wshub.Hub
+
+	scraperScheduler *scheduler.Scheduler
+
+	votesMu        sync.Mutex
+	recordedVotes = []map[string]interface{}{
+		{
+			"bill_id":   "C-123",
+			"vote_date": "2024-01-20",
+			"result":    "passed",
+			"yea":       150,
+			"nay":       100,
+			"abstain":   5,
+		},
+	}
+)
+
+// initScheduler wires up the scraper job queue against the Postgres database
+// at SCRAPER_POSTGRES_DSN, falling back to the admin user store's
+// POSTGRES_DSN. Like the admin subsystem, the gateway still starts with
+// neither configured; /api/v1/scrape then answers 503 until one is provided.
+func initScheduler() {
+	dsn := os.Getenv("SCRAPER_POSTGRES_DSN")
+	if dsn == "" {
+		dsn = os.Getenv("POSTGRES_DSN")
+	}
+	if dsn == "" {
+		logger.Warn("SCRAPER_POSTGRES_DSN not set, /api/v1/scrape will be unavailable")
+		return
+	}
+
+	store, err := scheduler.NewStore(dsn)
+	if err != nil {
+		logger.Warnf("Scraper job store unavailable: %v", err)
+		return
+	}
+
+	scraperScheduler = scheduler.New(store, runScrapeJob, nil)
+}
+
+// runScrapeJob is a placeholder scrape implementation: the gateway doesn't
+// own a scraper pipeline itself, so this simulates fetching a handful of
+// pages and streams progress over the job's WebSocket topic so the
+// scheduler's retry/cancel/progress machinery can be exercised end to end.
+func runScrapeJob(ctx context.Context, job *scheduler.Job, progress func(pages, records, errs int)) error {
+	const totalPages = 3
+	for page := 1; page <= totalPages; page++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		progress(page, page*10, 0)
+		if err := wsHub.Publish(ctx, "scrape:"+job.ID, map[string]interface{}{
+			"job_id":           job.ID,
+			"pages_fetched":    page,
+			"records_inserted": page * 10,
+		}); err != nil {
+			logger.Warnf("Publishing scrape progress for job %s: %v", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// initAdmin builds the JWT validator and, if POSTGRES_DSN is configured,
+// the Postgres-backed user store. The gateway still starts without a DSN;
+// /admin/users then answers 503 until one is provided.
+func initAdmin() {
+	authValidator = auth.NewValidator(os.Getenv("ADMIN_JWT_HS256_SECRET"), os.Getenv("ADMIN_JWT_JWKS_URL"))
+
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		logger.Warn("POSTGRES_DSN not set, /api/v1/admin/users will be unavailable")
+		return
+	}
+	store, err := users.NewStore(dsn)
+	if err != nil {
+		logger.Warnf("Admin user store unavailable: %v", err)
+		return
+	}
+	userStore = store
+}
+
+// loadGeoData populates boundaryIndex, postcodeIndex and representatives
+// from local seed files, then attempts to refresh them from the upstream
+// Statistics Canada / Canada Post sources if configured via env vars.
+// Failures are logged and swallowed so the gateway still starts serving
+// whatever data loaded successfully.
+func loadGeoData() {
+	boundaryIndex = geo.NewIndex()
+	postcodeIndex = geo.NewPostcodeIndex()
+	geoCache = cache.New(os.Getenv("REDIS_ADDR"), 10*time.Minute)
+
+	dataDir := os.Getenv("GEO_DATA_DIR")
+	if dataDir == "" {
+		dataDir = "./data"
+	}
+	if err := boundaryIndex.LoadGeoJSONFile(dataDir+"/boundaries_federal.geojson", "federal"); err != nil {
+		logger.Warnf("Loading federal boundaries: %v", err)
+	}
+	if err := postcodeIndex.LoadCSVFile(dataDir + "/postcodes.csv"); err != nil {
+		logger.Warnf("Loading postcode centroids: %v", err)
+	}
+	representatives = loadRepresentatives(dataDir + "/representatives.json")
+
+	loader := geo.NewLoader(geo.LoaderConfig{
+		FederalBoundariesURL:    os.Getenv("STATCAN_FEDERAL_BOUNDARIES_URL"),
+		ProvincialBoundariesURL: os.Getenv("STATCAN_PROVINCIAL_BOUNDARIES_URL"),
+		MunicipalBoundariesURL:  os.Getenv("STATCAN_MUNICIPAL_BOUNDARIES_URL"),
+		PostcodeCentroidsURL:    os.Getenv("CANADAPOST_FSA_CENTROIDS_URL"),
+	})
+	if err := loader.Load(boundaryIndex, postcodeIndex); err != nil {
+		logger.Warnf("Refreshing geo data from upstream sources: %v", err)
+	}
+
+	logger.Infof("Loaded %d boundaries and %d postcode FSAs", boundaryIndex.Len(), postcodeIndex.Len())
+}
+
+func loadRepresentatives(path string) map[string][]map[string]interface{} {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		logger.Warnf("Loading representatives: %v", err)
+		return map[string][]map[string]interface{}{}
+	}
+	var reps map[string][]map[string]interface{}
+	if err := json.Unmarshal(raw, &reps); err != nil {
+		logger.Warnf("Parsing representatives: %v", err)
+		return map[string][]map[string]interface{}{}
+	}
+	return reps
+}
+
+// routeTemplate returns the matched route's path template (e.g.
+// "/api/v1/represent/postcode/{postcode}") so request metrics are grouped
+// by endpoint rather than exploding in cardinality on path parameters.
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unmatched"
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return tmpl
+}
+
+// defaultRequestTimeout is the deadline applied to requests that don't send
+// X-Request-Timeout, configurable via REQUEST_DEFAULT_TIMEOUT (a
+// time.ParseDuration string, e.g. "30s").
+func defaultRequestTimeout() time.Duration {
+	if v := os.Getenv("REQUEST_DEFAULT_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return 30 * time.Second
+}
+
+// deadlineMiddleware attaches a context.WithTimeout to every request, so a
+// client disconnect or slow downstream call (OPA evaluation, a scraper
+// fetch) is actually cancelled rather than left running. Clients can
+// request a tighter or looser deadline via the X-Request-Timeout header
+// (a time.ParseDuration string, e.g. "5s").
+func deadlineMiddleware(defaultTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := defaultTimeout
+			if v := r.Header.Get("X-Request-Timeout"); v != "" {
+				if d, err := time.ParseDuration(v); err == nil {
+					timeout = d
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 func main() {
 	// Configure logging
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetOutput(os.Stdout)
 	logger.SetLevel(logrus.InfoLevel)
 
+	// Load the policy bundle directory (falls back to an empty engine so the
+	// gateway still starts if no policies have been provisioned yet).
+	bundleDir := os.Getenv("POLICY_BUNDLE_DIR")
+	if bundleDir == "" {
+		bundleDir = "./policies"
+	}
+	engine, err := policy.NewEngine(bundleDir)
+	if err != nil {
+		logger.Warnf("Policy engine failed to load bundle %s: %v", bundleDir, err)
+		engine, _ = policy.NewEngine("")
+	}
+	policyEngine = engine
+	go policyEngine.Watch(context.Background(), 5*time.Second, func(err error) {
+		logger.Errorf("Policy bundle reload failed: %v", err)
+	})
+
+	loadGeoData()
+	initAdmin()
+	wsHub = wshub.NewHub(os.Getenv("REDIS_ADDR"))
+	initScheduler()
+
 	// Initialize router
 	r := mux.NewRouter()
+	r.Use(metrics.Middleware(routeTemplate))
+	r.Use(deadlineMiddleware(defaultRequestTimeout()))
+
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
 
 	// API routes
 	api := r.PathPrefix("/api/v1").Subrouter()
-	
+
 	// Health and status
 	api.HandleFunc("/health", healthHandler).Methods("GET")
 	api.HandleFunc("/status", statusHandler).Methods("GET")
@@ -75,15 +327,19 @@ func main() {
 	// Policy evaluation
 	api.HandleFunc("/policy/evaluate", policyHandler).Methods("POST")
 	api.HandleFunc("/policy/validate", policyValidationHandler).Methods("POST")
+	api.HandleFunc("/policy/batch", policyBatchHandler).Methods("POST")
 	
 	// Data scraping
 	api.HandleFunc("/scrape", scraperHandler).Methods("POST")
+	api.HandleFunc("/scrape/jobs", scrapeJobsHandler).Methods("GET")
+	api.HandleFunc("/scrape/jobs/{id}", scrapeJobHandler).Methods("GET")
+	api.HandleFunc("/scrape/jobs/{id}", cancelScrapeJobHandler).Methods("DELETE")
 	api.HandleFunc("/scrape/{jurisdiction}", scraperByJurisdictionHandler).Methods("GET")
 	
 	// Parliament data
 	api.HandleFunc("/parliament/bills", parliamentBillsHandler).Methods("GET")
 	api.HandleFunc("/parliament/politicians", parliamentPoliticiansHandler).Methods("GET")
-	api.HandleFunc("/parliament/votes", parliamentVotesHandler).Methods("GET")
+	api.HandleFunc("/parliament/votes", parliamentVotesHandler).Methods("GET", "POST")
 	
 	// Civic data
 	api.HandleFunc("/civic/meetings", civicMeetingsHandler).Methods("GET")
@@ -98,9 +354,21 @@ func main() {
 	
 	// Admin endpoints
 	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(auth.Middleware(authValidator, policyEngine, func(decisionID string, claims *auth.Claims, allowed bool, r *http.Request) {
+		logger.WithFields(logrus.Fields{
+			"decision_id": decisionID,
+			"user":        claims.Subject,
+			"allowed":     allowed,
+			"path":        r.URL.Path,
+		}).Info("admin authorization decision")
+	}))
 	admin.HandleFunc("/policies", adminPoliciesHandler).Methods("GET", "POST", "PUT", "DELETE")
 	admin.HandleFunc("/users", adminUsersHandler).Methods("GET", "POST", "PUT", "DELETE")
 
+	// Live streaming endpoints
+	api.HandleFunc("/ws/votes", votesWebSocketHandler).Methods("GET")
+	api.HandleFunc("/ws/scrape/{job_id}", scrapeWebSocketHandler).Methods("GET")
+
 	// Configure CORS
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
@@ -117,8 +385,46 @@ func main() {
 		port = "9009"
 	}
 
-	logger.Infof("Starting OpenPolicyAshBack2 API server on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, handler))
+	server := &http.Server{Addr: ":" + port, Handler: handler}
+
+	go func() {
+		logger.Infof("Starting OpenPolicyAshBack2 API server on port %s", port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	logger.Info("Shutting down gracefully")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wsHub.Shutdown(ctx)
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Errorf("Server shutdown error: %v", err)
+	}
+}
+
+func votesWebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := wshub.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Errorf("WebSocket upgrade failed: %v", err)
+		return
+	}
+	wsHub.Subscribe("votes", conn)
+}
+
+func scrapeWebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	jobID := mux.Vars(r)["job_id"]
+
+	conn, err := wshub.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Errorf("WebSocket upgrade failed: %v", err)
+		return
+	}
+	wsHub.Subscribe("scrape:"+jobID, conn)
 }
 
 func healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -168,50 +474,154 @@ func policyHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.Query == "" {
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	}
 
-	// Create OPA query
-	query := rego.New(
-		rego.Query("data.example.allow"),
-		rego.Input(req.Input),
-	)
-
-	// Execute query
-	ctx := context.Background()
-	results, err := query.Eval(ctx)
+	result, err := policyEngine.Evaluate(r.Context(), req.Query, req.Input, req.Unknowns)
 	if err != nil {
 		logger.Errorf("Policy evaluation error: %v", err)
-		response := PolicyResponse{
-			Result: false,
-			Error:  "Policy evaluation failed",
-		}
+		response := PolicyResponse{Error: err.Error()}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	// Process results
-	var response PolicyResponse
-	if len(results) > 0 && len(results[0].Expressions) > 0 {
-		if allowed, ok := results[0].Expressions[0].Value.(bool); ok {
-			response.Result = allowed
-		}
+	response := PolicyResponse{
+		Result:     result.Result,
+		Results:    result.Results,
+		Queries:    result.Queries,
+		DecisionID: result.DecisionID,
 	}
 
-	// Return response
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 func policyValidationHandler(w http.ResponseWriter, r *http.Request) {
-	// Validate policy syntax and structure
-	response := map[string]interface{}{
-		"valid":   true,
-		"message": "Policy validation successful",
+	src, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
+	result := policy.ValidateModule(string(src))
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if !result.Valid {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// batchConcurrency caps how many queries in a single /policy/batch request
+// are evaluated at once, configurable via POLICY_BATCH_CONCURRENCY.
+func batchConcurrency() int {
+	if v := os.Getenv("POLICY_BATCH_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 16
+}
+
+// BatchQueryRequest is one entry in a POST /policy/batch request.
+type BatchQueryRequest struct {
+	ID    string                 `json:"id"`
+	Query string                 `json:"query"`
+	Input map[string]interface{} `json:"input"`
+}
+
+// BatchRequest is the body of POST /policy/batch.
+type BatchRequest struct {
+	Queries []BatchQueryRequest `json:"queries"`
+	DryRun  bool                `json:"dry_run,omitempty"`
+}
+
+// BatchQueryResult is the outcome of one query within a batch. Results is
+// only populated in dry-run mode, where callers want to see exactly which
+// expressions fired rather than just the collapsed boolean.
+type BatchQueryResult struct {
+	ID         string                   `json:"id"`
+	Result     bool                     `json:"result"`
+	Results    []map[string]interface{} `json:"results,omitempty"`
+	DecisionID string                   `json:"decision_id,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+func evalBatchQuery(ctx context.Context, q BatchQueryRequest, dryRun bool) BatchQueryResult {
+	if q.Query == "" {
+		return BatchQueryResult{ID: q.ID, Error: "query is required"}
+	}
+
+	result, err := policyEngine.Evaluate(ctx, q.Query, q.Input, nil)
+	if err != nil {
+		return BatchQueryResult{ID: q.ID, Error: err.Error()}
+	}
+
+	out := BatchQueryResult{ID: q.ID, Result: result.Result, DecisionID: result.DecisionID}
+	if dryRun {
+		out.Results = result.Results
+	}
+	return out
+}
+
+// policyBatchHandler evaluates a batch of independent queries concurrently
+// against the cached PolicyEngine, capping fan-out with a semaphore. When
+// the client sends "Accept: application/x-ndjson" results are streamed out
+// as they complete instead of being buffered into one JSON array.
+func policyBatchHandler(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sem := make(chan struct{}, batchConcurrency())
+	var wg sync.WaitGroup
+
+	if strings.Contains(r.Header.Get("Accept"), "application/x-ndjson") {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		var writeMu sync.Mutex
+		enc := json.NewEncoder(w)
+
+		for _, q := range req.Queries {
+			wg.Add(1)
+			go func(q BatchQueryRequest) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				result := evalBatchQuery(r.Context(), q, req.DryRun)
+				writeMu.Lock()
+				enc.Encode(result)
+				if flusher != nil {
+					flusher.Flush()
+				}
+				writeMu.Unlock()
+			}(q)
+		}
+		wg.Wait()
+		return
+	}
+
+	results := make([]BatchQueryResult, len(req.Queries))
+	for i, q := range req.Queries {
+		wg.Add(1)
+		go func(i int, q BatchQueryRequest) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = evalBatchQuery(r.Context(), q, req.DryRun)
+		}(i, q)
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
 }
 
 func scraperHandler(w http.ResponseWriter, r *http.Request) {
@@ -220,22 +630,82 @@ func scraperHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.Jurisdiction == "" {
+		http.Error(w, "jurisdiction is required", http.StatusBadRequest)
+		return
+	}
 
-	// Mock scraper response - in real implementation, this would call the scraper services
-	response := ScraperResponse{
-		Success: true,
-		Data: []map[string]interface{}{
-			{
-				"jurisdiction": req.Jurisdiction,
-				"type":         req.Type,
-				"scraped_at":   time.Now(),
-				"count":        0,
-			},
-		},
+	w.Header().Set("Content-Type", "application/json")
+
+	if scraperScheduler == nil {
+		http.Error(w, "scraper job queue is not configured", http.StatusServiceUnavailable)
+		return
 	}
 
+	job, err := scraperScheduler.Enqueue(r.Context(), scheduler.EnqueueRequest{
+		Jurisdiction:   req.Jurisdiction,
+		Type:           req.Type,
+		Parameters:     req.Parameters,
+		IdempotencyKey: req.IdempotencyKey,
+		CronSchedule:   req.CronSchedule,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"job_id":     job.ID,
+		"status_url": "/api/v1/scrape/jobs/" + job.ID,
+	})
+}
+
+func scrapeJobHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if scraperScheduler == nil {
+		http.Error(w, "scraper job queue is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	job, err := scraperScheduler.Store().Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(job)
+}
+
+func scrapeJobsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if scraperScheduler == nil {
+		http.Error(w, "scraper job queue is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	status := scheduler.Status(r.URL.Query().Get("status"))
+	jobs, err := scraperScheduler.Store().List(r.Context(), status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"jobs": jobs})
+}
+
+func cancelScrapeJobHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if scraperScheduler == nil {
+		http.Error(w, "scraper job queue is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := scraperScheduler.Cancel(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id": id, "status": "cancelled"})
 }
 
 func scraperByJurisdictionHandler(w http.ResponseWriter, r *http.Request) {
@@ -296,24 +766,46 @@ func parliamentPoliticiansHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// parliamentVotesHandler serves recorded vote results and, on POST, records a
+// new one and broadcasts it to /ws/votes subscribers.
 func parliamentVotesHandler(w http.ResponseWriter, r *http.Request) {
-	// Mock votes data
-	response := map[string]interface{}{
-		"votes": []map[string]interface{}{
-			{
-				"bill_id":    "C-123",
-				"vote_date":  "2024-01-20",
-				"result":     "passed",
-				"yea":        150,
-				"nay":        100,
-				"abstain":    5,
-			},
-		},
-		"total": 1,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+
+	switch r.Method {
+	case http.MethodGet:
+		votesMu.Lock()
+		votes := append([]map[string]interface{}{}, recordedVotes...)
+		votesMu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"votes": votes,
+			"total": len(votes),
+		})
+
+	case http.MethodPost:
+		var req VoteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.BillID == "" {
+			http.Error(w, "bill_id is required", http.StatusBadRequest)
+			return
+		}
+
+		vote := map[string]interface{}{
+			"bill_id":   req.BillID,
+			"vote_date": req.VoteDate,
+			"result":    req.Result,
+			"yea":       req.Yea,
+			"nay":       req.Nay,
+			"abstain":   req.Abstain,
+		}
+
+		votesMu.Lock()
+		recordedVotes = append(recordedVotes, vote)
+		votesMu.Unlock()
+
+		if err := wsHub.Publish(r.Context(), "votes", vote); err != nil {
+			logger.Warnf("Publishing vote %s to /ws/votes subscribers: %v", req.BillID, err)
+		}
+		json.NewEncoder(w).Encode(vote)
+	}
 }
 
 func civicMeetingsHandler(w http.ResponseWriter, r *http.Request) {
@@ -383,26 +875,77 @@ func representativesHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func boundariesHandler(w http.ResponseWriter, r *http.Request) {
-	// Mock boundaries data
+	boundaries := boundaryIndex.All()
 	response := map[string]interface{}{
-		"boundaries": []map[string]interface{}{
-			{
-				"id":           "B001",
-				"name":         "Toronto Centre",
-				"level":        "federal",
-				"province":     "ON",
-				"population":   100000,
-				"area_km2":     25.5,
-				"geometry":     "POLYGON(...)",
-			},
-		},
-		"total": 1,
+		"boundaries": boundariesAsMaps(boundaries),
+		"total":      len(boundaries),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// boundaryAsMap renders a geo.Boundary the way the API has always shaped it.
+func boundaryAsMap(b *geo.Boundary) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         b.ID,
+		"name":       b.Name,
+		"level":      b.Level,
+		"province":   b.Province,
+		"population": b.Population,
+		"area_km2":   b.AreaKM2,
+	}
+}
+
+func boundariesAsMaps(boundaries []*geo.Boundary) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(boundaries))
+	for _, b := range boundaries {
+		out = append(out, boundaryAsMap(b))
+	}
+	return out
+}
+
+// representativesAndBoundariesFor resolves the representatives and
+// boundaries covering pt, annotating each representative with its distance
+// (in km) to their own office location, if the representative record carries
+// one. Boundary geometry (e.g. upstream Statistics Canada shapefiles) has no
+// notion of an office, so that location has to come from the representative
+// record itself rather than the boundary.
+func representativesAndBoundariesFor(pt geo.Point) ([]map[string]interface{}, []map[string]interface{}) {
+	boundaries := boundaryIndex.PointLookup(pt.Lat, pt.Lng)
+
+	var reps []map[string]interface{}
+	boundaryMaps := make([]map[string]interface{}, 0, len(boundaries))
+	for _, b := range boundaries {
+		bm := boundaryAsMap(b)
+		bm["contains_point"] = true
+		boundaryMaps = append(boundaryMaps, bm)
+
+		for _, rep := range representatives[b.ID] {
+			repCopy := map[string]interface{}{}
+			for k, v := range rep {
+				repCopy[k] = v
+			}
+			if office, ok := repOffice(rep); ok {
+				repCopy["distance_km"] = geo.DistanceKM(pt, office)
+			}
+			reps = append(reps, repCopy)
+		}
+	}
+	return reps, boundaryMaps
+}
+
+// repOffice extracts a representative's office location from its
+// "office_lat"/"office_lng" fields, if both are present.
+func repOffice(rep map[string]interface{}) (geo.Point, bool) {
+	lat, latOK := rep["office_lat"].(float64)
+	lng, lngOK := rep["office_lng"].(float64)
+	if !latOK || !lngOK {
+		return geo.Point{}, false
+	}
+	return geo.Point{Lat: lat, Lng: lng}, true
+}
+
 func postcodesHandler(w http.ResponseWriter, r *http.Request) {
 	// Mock postcodes data
 	response := map[string]interface{}{
@@ -430,28 +973,19 @@ func pointToRepresentativesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Mock response for point lookup
-	response := RepresentativeResponse{
-		Representatives: []map[string]interface{}{
-			{
-				"id":           "R001",
-				"name":         "John Smith",
-				"party":        "Liberal",
-				"riding":       "Toronto Centre",
-				"level":        "federal",
-				"distance_km":  0.5,
-			},
-		},
-		Boundaries: []map[string]interface{}{
-			{
-				"id":           "B001",
-				"name":         "Toronto Centre",
-				"level":        "federal",
-				"contains_point": true,
-			},
-		},
+	cacheKey := fmt.Sprintf("point:%f,%f", req.Latitude, req.Longitude)
+	var response RepresentativeResponse
+	if geoCache.Get(r.Context(), cacheKey, &response) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
 	}
 
+	pt := geo.Point{Lat: req.Latitude, Lng: req.Longitude}
+	reps, boundaries := representativesAndBoundariesFor(pt)
+	response = RepresentativeResponse{Representatives: reps, Boundaries: boundaries}
+
+	geoCache.Set(r.Context(), cacheKey, response)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -460,50 +994,145 @@ func postcodeToRepresentativesHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	postcode := vars["postcode"]
 
-	// Mock response for postcode lookup
-	response := RepresentativeResponse{
-		Representatives: []map[string]interface{}{
-			{
-				"id":           "R001",
-				"name":         "John Smith",
-				"party":        "Liberal",
-				"riding":       "Toronto Centre",
-				"level":        "federal",
-				"postcode":     postcode,
-			},
-		},
-		Boundaries: []map[string]interface{}{
-			{
-				"id":           "B001",
-				"name":         "Toronto Centre",
-				"level":        "federal",
-				"postcode":     postcode,
-			},
-		},
+	cacheKey := "postcode:" + postcode
+	var response RepresentativeResponse
+	if geoCache.Get(r.Context(), cacheKey, &response) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
 	}
 
+	centroid, ok := postcodeIndex.Lookup(postcode)
+	if !ok {
+		response = RepresentativeResponse{Error: fmt.Sprintf("unknown postcode %q", postcode)}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	reps, boundaries := representativesAndBoundariesFor(centroid)
+	response = RepresentativeResponse{Representatives: reps, Boundaries: boundaries}
+
+	geoCache.Set(r.Context(), cacheKey, response)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// AdminPolicyRequest is the body accepted for POST/PUT /admin/policies: a
+// named Rego module to compile and hot-swap into the running PolicyEngine.
+type AdminPolicyRequest struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
 func adminPoliciesHandler(w http.ResponseWriter, r *http.Request) {
-	// Admin policy management
-	response := map[string]interface{}{
-		"message": "Admin policy management endpoint",
-		"method":  r.Method,
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"policies": policyEngine.ListModules(),
+		})
+
+	case http.MethodPost, http.MethodPut:
+		var req AdminPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" {
+			http.Error(w, "id and source are required", http.StatusBadRequest)
+			return
+		}
+		if err := policyEngine.PutModule(req.ID, req.Source); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": req.ID, "status": "compiled"})
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			var req AdminPolicyRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			id = req.ID
+		}
+		if id == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		if err := policyEngine.DeleteModule(id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "status": "deleted"})
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+// AdminUserRequest is the body accepted for POST (create) and PUT (password
+// reset) on /admin/users.
+type AdminUserRequest struct {
+	ID       string `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
 }
 
 func adminUsersHandler(w http.ResponseWriter, r *http.Request) {
-	// Admin user management
-	response := map[string]interface{}{
-		"message": "Admin user management endpoint",
-		"method":  r.Method,
+	w.Header().Set("Content-Type", "application/json")
+
+	if userStore == nil {
+		http.Error(w, "admin user store is not configured", http.StatusServiceUnavailable)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	switch r.Method {
+	case http.MethodGet:
+		list, err := userStore.List(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"users": list})
+
+	case http.MethodPost:
+		var req AdminUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Username == "" || req.Password == "" {
+			http.Error(w, "username and password are required", http.StatusBadRequest)
+			return
+		}
+		u, err := userStore.Create(r.Context(), req.Username, req.Password)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(u)
+
+	case http.MethodPut:
+		var req AdminUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" || req.Password == "" {
+			http.Error(w, "id and password are required", http.StatusBadRequest)
+			return
+		}
+		if err := userStore.UpdatePassword(r.Context(), req.ID, req.Password); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": req.ID, "status": "updated"})
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			var req AdminUserRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			id = req.ID
+		}
+		if id == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		if err := userStore.Delete(r.Context(), id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "status": "deleted"})
+	}
 } 
\ No newline at end of file
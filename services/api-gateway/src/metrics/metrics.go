@@ -0,0 +1,84 @@
+// Package metrics holds the gateway's Prometheus collectors and the HTTP
+// middleware that feeds them.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// HTTPRequestsTotal counts every request the gateway has served, by
+	// route template, method and response status.
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests handled.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration tracks how long requests take, by route and
+	// method.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// PolicyEvalDuration tracks OPA evaluation latency, by query and
+	// whether the decision was allow/deny.
+	PolicyEvalDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "policy_eval_duration_seconds",
+		Help:    "Rego policy evaluation latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"query", "decision"})
+
+	// ScraperQueueDepth is the number of scrape jobs currently queued or
+	// running for a jurisdiction.
+	ScraperQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "scraper_queue_depth",
+		Help: "Number of scrape jobs queued or running, by jurisdiction.",
+	}, []string{"jurisdiction"})
+)
+
+// ObservePolicyEval records a completed policy evaluation.
+func ObservePolicyEval(query string, allowed bool, d time.Duration) {
+	PolicyEvalDuration.WithLabelValues(query, strconv.FormatBool(allowed)).Observe(d.Seconds())
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code
+// written, since net/http doesn't expose it otherwise.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// RouteTemplateFunc maps a request to its matched route's path template; it
+// is a plain function type so this package doesn't need to import gorilla/mux.
+type RouteTemplateFunc func(r *http.Request) string
+
+// Middleware records HTTPRequestsTotal and HTTPRequestDuration for every
+// request. routeTemplate extracts the matched route's path template (e.g.
+// "/api/v1/represent/postcode/{postcode}") so metrics don't explode in
+// cardinality from path parameters.
+func Middleware(routeTemplate RouteTemplateFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			route := routeTemplate(r)
+			HTTPRequestsTotal.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+			HTTPRequestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		})
+	}
+}
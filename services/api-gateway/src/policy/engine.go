@@ -0,0 +1,360 @@
+// Package policy loads and evaluates Rego policy bundles for the API gateway.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/metrics"
+)
+
+// Engine compiles a set of Rego modules loaded from a bundle directory and
+// serves prepared, cached queries against them. It is safe for concurrent use.
+type Engine struct {
+	dir string
+
+	mu       sync.RWMutex
+	modules  map[string]*ast.Module
+	compiler *ast.Compiler
+
+	// prepared holds a *sync.Map of query string -> rego.PreparedEvalQuery.
+	// It's swapped atomically on reload so concurrent Evaluate calls never
+	// observe a torn/reassigned map.
+	prepared atomic.Pointer[sync.Map]
+}
+
+// EvalResult is the outcome of evaluating a query, including enough detail
+// for audit logging (DecisionID) and partial evaluation (Queries).
+type EvalResult struct {
+	DecisionID string                   `json:"decision_id"`
+	Result     bool                     `json:"result"`
+	Results    []map[string]interface{} `json:"results,omitempty"`
+	Queries    []string                 `json:"queries,omitempty"`
+}
+
+// NewEngine creates an Engine and performs an initial load from dir. dir may
+// be empty, in which case the engine starts with no modules compiled and
+// LoadDir can be called later once a bundle location is known.
+func NewEngine(dir string) (*Engine, error) {
+	e := &Engine{
+		dir:     dir,
+		modules: map[string]*ast.Module{},
+	}
+	e.prepared.Store(&sync.Map{})
+	if dir == "" {
+		return e, nil
+	}
+	if err := e.LoadDir(dir); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// LoadDir walks dir for *.rego files, parses and compiles them, and swaps
+// them in atomically. Any previously prepared queries are invalidated since
+// they may reference rules that no longer exist.
+func (e *Engine) LoadDir(dir string) error {
+	modules := map[string]*ast.Module{}
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".rego") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		mod, err := ast.ParseModule(path, string(src))
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		modules[path] = mod
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	compiler := ast.NewCompiler()
+	compiler.Compile(modules)
+	if compiler.Failed() {
+		return compiler.Errors
+	}
+
+	e.mu.Lock()
+	e.dir = dir
+	e.modules = modules
+	e.compiler = compiler
+	e.mu.Unlock()
+
+	e.prepared.Store(&sync.Map{})
+	return nil
+}
+
+// PutModule hot-swaps a single Rego module into the engine: it validates
+// src, writes it to <bundle dir>/<id>.rego, and recompiles the whole bundle.
+// If the new module fails to compile alongside the existing ones, the write
+// is rolled back and the previous compiled set is left in place.
+func (e *Engine) PutModule(id, src string) error {
+	if _, err := ast.ParseModule(id+".rego", src); err != nil {
+		return fmt.Errorf("parsing module %q: %w", id, err)
+	}
+
+	e.mu.RLock()
+	dir := e.dir
+	e.mu.RUnlock()
+	if dir == "" {
+		return fmt.Errorf("policy engine has no bundle directory configured")
+	}
+
+	path := filepath.Join(dir, id+".rego")
+	previous, readErr := os.ReadFile(path)
+	hadPrevious := readErr == nil
+
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		return fmt.Errorf("writing module %q: %w", id, err)
+	}
+
+	if err := e.LoadDir(dir); err != nil {
+		if hadPrevious {
+			os.WriteFile(path, previous, 0o644)
+		} else {
+			os.Remove(path)
+		}
+		_ = e.LoadDir(dir)
+		return fmt.Errorf("compiling module %q: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteModule removes a module previously added with PutModule and
+// recompiles the remaining bundle.
+func (e *Engine) DeleteModule(id string) error {
+	e.mu.RLock()
+	dir := e.dir
+	e.mu.RUnlock()
+	if dir == "" {
+		return fmt.Errorf("policy engine has no bundle directory configured")
+	}
+
+	path := filepath.Join(dir, id+".rego")
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing module %q: %w", id, err)
+	}
+	return e.LoadDir(dir)
+}
+
+// ListModules returns the IDs (file names without the .rego suffix) of
+// every module currently loaded.
+func (e *Engine) ListModules() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ids := make([]string, 0, len(e.modules))
+	for path := range e.modules {
+		id := strings.TrimSuffix(filepath.Base(path), ".rego")
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Watch polls the bundle directory every interval and reloads it whenever a
+// module's contents change. It runs until ctx is cancelled, logging reload
+// failures to errFn rather than aborting the watch loop.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration, errFn func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastHash := e.snapshotHash()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hash := e.snapshotHash()
+			if hash == lastHash {
+				continue
+			}
+			e.mu.RLock()
+			dir := e.dir
+			e.mu.RUnlock()
+			if err := e.LoadDir(dir); err != nil && errFn != nil {
+				errFn(err)
+				continue
+			}
+			lastHash = hash
+		}
+	}
+}
+
+// snapshotHash is a cheap fingerprint (path + mtime) used to detect whether
+// the bundle directory changed since the last poll.
+func (e *Engine) snapshotHash() string {
+	e.mu.RLock()
+	dir := e.dir
+	e.mu.RUnlock()
+	if dir == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".rego") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(&b, "%s:%d;", path, info.ModTime().UnixNano())
+		return nil
+	})
+	return b.String()
+}
+
+// preparedQuery returns a cached rego.PreparedEvalQuery for query, compiling
+// and storing it on first use.
+func (e *Engine) preparedQuery(ctx context.Context, query string) (rego.PreparedEvalQuery, error) {
+	prepared := e.prepared.Load()
+	if cached, ok := prepared.Load(query); ok {
+		return cached.(rego.PreparedEvalQuery), nil
+	}
+
+	e.mu.RLock()
+	compiler := e.compiler
+	e.mu.RUnlock()
+	if compiler == nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("policy engine has no compiled modules loaded")
+	}
+
+	pq, err := rego.New(
+		rego.Query(query),
+		rego.Compiler(compiler),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, fmt.Errorf("preparing query %q: %w", query, err)
+	}
+
+	actual, _ := prepared.LoadOrStore(query, pq)
+	return actual.(rego.PreparedEvalQuery), nil
+}
+
+// Evaluate runs query against input and returns the full result set plus a
+// decision ID for auditing. If unknowns is non-empty, partial evaluation is
+// performed instead and the residual queries are returned in Queries.
+func (e *Engine) Evaluate(ctx context.Context, query string, input map[string]interface{}, unknowns []string) (*EvalResult, error) {
+	decisionID := uuid.NewString()
+	start := time.Now()
+
+	if len(unknowns) > 0 {
+		return e.evaluatePartial(ctx, query, input, unknowns, decisionID)
+	}
+
+	pq, err := e.preparedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := pq.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("evaluating query %q: %w", query, err)
+	}
+
+	result := &EvalResult{DecisionID: decisionID}
+	defer func() { metrics.ObservePolicyEval(query, result.Result, time.Since(start)) }()
+	for _, r := range rs {
+		row := map[string]interface{}{}
+		for i, expr := range r.Expressions {
+			row[fmt.Sprintf("expr%d", i)] = expr.Value
+			if allowed, ok := expr.Value.(bool); ok {
+				result.Result = result.Result || allowed
+			}
+		}
+		result.Results = append(result.Results, row)
+	}
+	return result, nil
+}
+
+func (e *Engine) evaluatePartial(ctx context.Context, query string, input map[string]interface{}, unknowns []string, decisionID string) (*EvalResult, error) {
+	e.mu.RLock()
+	compiler := e.compiler
+	e.mu.RUnlock()
+	if compiler == nil {
+		return nil, fmt.Errorf("policy engine has no compiled modules loaded")
+	}
+
+	unknownRefs := make([]string, len(unknowns))
+	copy(unknownRefs, unknowns)
+
+	ppq, err := rego.New(
+		rego.Query(query),
+		rego.Compiler(compiler),
+		rego.Input(input),
+		rego.Unknowns(unknownRefs),
+	).PrepareForPartial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("preparing partial query %q: %w", query, err)
+	}
+
+	pqs, err := ppq.Partial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("partial evaluation of %q: %w", query, err)
+	}
+
+	result := &EvalResult{DecisionID: decisionID}
+	for _, q := range pqs.Queries {
+		result.Queries = append(result.Queries, q.String())
+	}
+	return result, nil
+}
+
+// ValidationResult reports whether a submitted Rego module parses cleanly,
+// including row/column positions for any syntax errors.
+type ValidationResult struct {
+	Valid   bool              `json:"valid"`
+	Package string            `json:"package,omitempty"`
+	Errors  []ValidationError `json:"errors,omitempty"`
+}
+
+// ValidationError mirrors the location info in ast.Errors so API clients can
+// point editors at the exact offending line.
+type ValidationError struct {
+	Message string `json:"message"`
+	Row     int    `json:"row"`
+	Col     int    `json:"col"`
+}
+
+// ValidateModule parses src as a standalone Rego module and reports any
+// syntax errors with their source location.
+func ValidateModule(src string) *ValidationResult {
+	mod, err := ast.ParseModule("submitted.rego", src)
+	if err != nil {
+		astErrs, ok := err.(ast.Errors)
+		if !ok {
+			return &ValidationResult{Valid: false, Errors: []ValidationError{{Message: err.Error()}}}
+		}
+		result := &ValidationResult{Valid: false}
+		for _, e := range astErrs {
+			result.Errors = append(result.Errors, ValidationError{
+				Message: e.Message,
+				Row:     e.Location.Row,
+				Col:     e.Location.Col,
+			})
+		}
+		return result
+	}
+	return &ValidationResult{Valid: true, Package: mod.Package.Path.String()}
+}
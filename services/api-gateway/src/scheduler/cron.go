@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule is a parsed standard 5-field cron expression:
+// minute hour day-of-month month day-of-week.
+type schedule struct {
+	minute, hour, dom, month, dow fieldSet
+}
+
+// fieldSet is the set of values a cron field matches, e.g. {0, 15, 30, 45}
+// for "*/15".
+type fieldSet map[int]bool
+
+func (s schedule) matches(t time.Time) bool {
+	return s.minute[t.Minute()] &&
+		s.hour[t.Hour()] &&
+		s.dom[t.Day()] &&
+		s.month[int(t.Month())] &&
+		s.dow[int(t.Weekday())]
+}
+
+// parseCron parses a standard 5-field cron expression. It supports "*",
+// explicit values, comma-separated lists, "a-b" ranges, and "*/n" steps —
+// enough for the recurring scrape schedules the admin API exposes.
+func parseCron(expr string) (schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return schedule{}, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	minute, err := parseField(fields[0], 0, 59)
+	if err != nil {
+		return schedule{}, fmt.Errorf("minute: %w", err)
+	}
+	hour, err := parseField(fields[1], 0, 23)
+	if err != nil {
+		return schedule{}, fmt.Errorf("hour: %w", err)
+	}
+	dom, err := parseField(fields[2], 1, 31)
+	if err != nil {
+		return schedule{}, fmt.Errorf("day-of-month: %w", err)
+	}
+	month, err := parseField(fields[3], 1, 12)
+	if err != nil {
+		return schedule{}, fmt.Errorf("month: %w", err)
+	}
+	dow, err := parseField(fields[4], 0, 6)
+	if err != nil {
+		return schedule{}, fmt.Errorf("day-of-week: %w", err)
+	}
+
+	return schedule{minute: minute, hour: hour, dom: dom, month: month, dow: dow}, nil
+}
+
+func parseField(field string, min, max int) (fieldSet, error) {
+	set := fieldSet{}
+	for _, part := range strings.Split(field, ",") {
+		if err := parsePart(part, min, max, set); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+func parsePart(part string, min, max int, set fieldSet) error {
+	step := 1
+	base := part
+	if i := strings.IndexByte(part, '/'); i >= 0 {
+		base = part[:i]
+		n, err := strconv.Atoi(part[i+1:])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid step in %q", part)
+		}
+		step = n
+	}
+
+	lo, hi := min, max
+	switch {
+	case base == "*":
+		// full range, already defaulted
+	case strings.Contains(base, "-"):
+		bounds := strings.SplitN(base, "-", 2)
+		a, err1 := strconv.Atoi(bounds[0])
+		b, err2 := strconv.Atoi(bounds[1])
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("invalid range %q", base)
+		}
+		lo, hi = a, b
+	default:
+		v, err := strconv.Atoi(base)
+		if err != nil {
+			return fmt.Errorf("invalid value %q", base)
+		}
+		lo, hi = v, v
+	}
+
+	for v := lo; v <= hi; v += step {
+		if v < min || v > max {
+			return fmt.Errorf("value %d out of range [%d,%d]", v, min, max)
+		}
+		set[v] = true
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronStepField(t *testing.T) {
+	sched, err := parseCron("*/15 * * * *")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+	for _, minute := range []int{0, 15, 30, 45} {
+		if !sched.minute[minute] {
+			t.Errorf("expected minute %d to match */15", minute)
+		}
+	}
+	for _, minute := range []int{1, 14, 44, 59} {
+		if sched.minute[minute] {
+			t.Errorf("expected minute %d not to match */15", minute)
+		}
+	}
+}
+
+func TestParseCronRangeAndList(t *testing.T) {
+	sched, err := parseCron("0 9-11,17 * * 1-5")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+	for _, hour := range []int{9, 10, 11, 17} {
+		if !sched.hour[hour] {
+			t.Errorf("expected hour %d to match 9-11,17", hour)
+		}
+	}
+	for _, hour := range []int{8, 12, 16, 18} {
+		if sched.hour[hour] {
+			t.Errorf("expected hour %d not to match 9-11,17", hour)
+		}
+	}
+	for dow := 1; dow <= 5; dow++ {
+		if !sched.dow[dow] {
+			t.Errorf("expected weekday %d to match 1-5", dow)
+		}
+	}
+	if sched.dow[0] || sched.dow[6] {
+		t.Error("expected weekend days not to match 1-5")
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	cases := []string{
+		"* * * *",     // too few fields
+		"60 * * * *",  // minute out of range
+		"* * * * 8",   // day-of-week out of range
+		"*/0 * * * *", // zero step
+		"a * * * *",   // non-numeric value
+	}
+	for _, expr := range cases {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) = nil error, want error", expr)
+		}
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	// Every weekday at 09:00.
+	sched, err := parseCron("0 9 * * 1-5")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+
+	monday9am := time.Date(2026, time.August, 3, 9, 0, 0, 0, time.UTC) // a Monday
+	if !sched.matches(monday9am) {
+		t.Error("expected Monday 09:00 to match")
+	}
+
+	monday910am := time.Date(2026, time.August, 3, 9, 10, 0, 0, time.UTC)
+	if sched.matches(monday910am) {
+		t.Error("expected Monday 09:10 not to match (minute mismatch)")
+	}
+
+	saturday9am := time.Date(2026, time.August, 1, 9, 0, 0, 0, time.UTC) // a Saturday
+	if sched.matches(saturday9am) {
+		t.Error("expected Saturday 09:00 not to match (day-of-week mismatch)")
+	}
+}
+
+func TestParseCronDayOfMonthAndMonth(t *testing.T) {
+	sched, err := parseCron("0 0 1,15 */3 *")
+	if err != nil {
+		t.Fatalf("parseCron: %v", err)
+	}
+	for _, dom := range []int{1, 15} {
+		if !sched.dom[dom] {
+			t.Errorf("expected day-of-month %d to match 1,15", dom)
+		}
+	}
+	if sched.dom[2] {
+		t.Error("expected day-of-month 2 not to match 1,15")
+	}
+	for _, month := range []int{1, 4, 7, 10} {
+		if !sched.month[month] {
+			t.Errorf("expected month %d to match */3", month)
+		}
+	}
+	if sched.month[2] {
+		t.Error("expected month 2 not to match */3")
+	}
+}
@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashishtandon/OpenPolicyAshBack2/services/api-gateway/src/metrics"
+)
+
+// maxAttempts is how many times in all a job is run (the first run plus its
+// retries) before it's moved to the dead-letter table.
+const maxAttempts = 5
+
+// RunFunc does the actual scraping work for a job. progress should be
+// called as pages/records/errors accumulate so job status reflects it; the
+// context is cancelled if the job is cancelled via the API.
+type RunFunc func(ctx context.Context, job *Job, progress func(pages, records, errs int)) error
+
+// EnqueueRequest is what callers submit to schedule a scrape.
+type EnqueueRequest struct {
+	Jurisdiction   string
+	Type           string
+	Parameters     map[string]string
+	IdempotencyKey string
+	CronSchedule   string // optional 5-field cron expression for recurring jobs
+}
+
+// Scheduler runs scrape jobs in a bounded worker pool per jurisdiction,
+// persists their state, and retries failures with exponential backoff
+// before giving up to the dead-letter table.
+type Scheduler struct {
+	store *Store
+	run   RunFunc
+
+	defaultConcurrency int
+	concurrency        map[string]int
+
+	mu        sync.Mutex
+	sems      map[string]chan struct{}
+	cancelers map[string]context.CancelFunc
+	depths    map[string]int
+}
+
+// New returns a Scheduler backed by store, executing jobs with run.
+// perJurisdictionConcurrency overrides the default concurrency (2) for
+// specific jurisdictions.
+func New(store *Store, run RunFunc, perJurisdictionConcurrency map[string]int) *Scheduler {
+	return &Scheduler{
+		store:              store,
+		run:                run,
+		defaultConcurrency: 2,
+		concurrency:        perJurisdictionConcurrency,
+		sems:               map[string]chan struct{}{},
+		cancelers:          map[string]context.CancelFunc{},
+		depths:             map[string]int{},
+	}
+}
+
+// adjustQueueDepth updates the in-flight job count for jurisdiction and
+// publishes it to the scraper_queue_depth gauge.
+func (s *Scheduler) adjustQueueDepth(jurisdiction string, delta int) {
+	s.mu.Lock()
+	s.depths[jurisdiction] += delta
+	depth := s.depths[jurisdiction]
+	s.mu.Unlock()
+	metrics.ScraperQueueDepth.WithLabelValues(jurisdiction).Set(float64(depth))
+}
+
+// Store exposes the underlying job store for read APIs (get/list).
+func (s *Scheduler) Store() *Store {
+	return s.store
+}
+
+func (s *Scheduler) semaphore(jurisdiction string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sem, ok := s.sems[jurisdiction]; ok {
+		return sem
+	}
+	n := s.defaultConcurrency
+	if v, ok := s.concurrency[jurisdiction]; ok {
+		n = v
+	}
+	sem := make(chan struct{}, n)
+	s.sems[jurisdiction] = sem
+	return sem
+}
+
+// Enqueue persists req as a new job and, unless it has a cron schedule,
+// starts running it as soon as a worker slot for its jurisdiction frees up.
+// A job sharing an already-used idempotency key is returned unchanged
+// rather than re-run.
+func (s *Scheduler) Enqueue(ctx context.Context, req EnqueueRequest) (*Job, error) {
+	job := &Job{
+		ID:             uuid.NewString(),
+		Jurisdiction:   req.Jurisdiction,
+		Type:           req.Type,
+		Parameters:     req.Parameters,
+		IdempotencyKey: req.IdempotencyKey,
+		CronSchedule:   req.CronSchedule,
+	}
+
+	job, existed, err := s.store.Insert(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if existed {
+		return job, nil
+	}
+
+	if job.CronSchedule != "" {
+		go s.runRecurring(job)
+		return job, nil
+	}
+
+	go s.dispatch(job.ID, job.Jurisdiction)
+	return job, nil
+}
+
+// Cancel requests that a running (or queued) job stop. The in-flight
+// RunFunc's context is cancelled if the job is currently executing.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	cancel, running := s.cancelers[id]
+	s.mu.Unlock()
+	if running {
+		cancel()
+	}
+	return s.store.UpdateStatus(ctx, id, StatusCancelled, "")
+}
+
+// dispatch waits for a worker slot in jurisdiction's semaphore, then runs
+// the job, retrying on failure with exponential backoff up to maxAttempts.
+func (s *Scheduler) dispatch(jobID, jurisdiction string) {
+	s.adjustQueueDepth(jurisdiction, 1)
+	defer s.adjustQueueDepth(jurisdiction, -1)
+
+	sem := s.semaphore(jurisdiction)
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	s.attempt(jobID)
+}
+
+func (s *Scheduler) attempt(jobID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancelers[jobID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.cancelers, jobID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	job, err := s.store.Get(ctx, jobID)
+	if err != nil {
+		return
+	}
+	if job.Status == StatusCancelled {
+		return
+	}
+
+	s.store.UpdateStatus(ctx, jobID, StatusRunning, "")
+	attempt, _ := s.store.IncrementAttempt(ctx, jobID)
+
+	runErr := s.run(ctx, job, func(pages, records, errs int) {
+		s.store.UpdateProgress(ctx, jobID, pages, records, errs)
+	})
+
+	if runErr == nil {
+		s.store.UpdateStatus(ctx, jobID, StatusSucceeded, "")
+		return
+	}
+
+	if ctx.Err() == context.Canceled {
+		return
+	}
+
+	if attempt >= maxAttempts {
+		s.store.UpdateStatus(ctx, jobID, StatusFailed, runErr.Error())
+		job.LastError = runErr.Error()
+		job.Attempt = attempt
+		s.store.MoveToDeadLetter(context.Background(), job)
+		return
+	}
+
+	s.store.UpdateStatus(ctx, jobID, StatusQueued, runErr.Error())
+	backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+	time.AfterFunc(backoff, func() { s.dispatch(jobID, job.Jurisdiction) })
+}
+
+// runRecurring re-enqueues a run of job every time its cron schedule
+// matches, until the job is cancelled or can no longer be read from the
+// store. It polls once a minute, the standard cron resolution.
+func (s *Scheduler) runRecurring(job *Job) {
+	sched, err := parseCron(job.CronSchedule)
+	if err != nil {
+		s.store.UpdateStatus(context.Background(), job.ID, StatusFailed, fmt.Sprintf("invalid cron schedule: %v", err))
+		return
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		current, err := s.store.Get(context.Background(), job.ID)
+		if err != nil || current.Status == StatusCancelled {
+			return
+		}
+		if sched.matches(now) {
+			go s.dispatch(job.ID, job.Jurisdiction)
+		}
+	}
+}
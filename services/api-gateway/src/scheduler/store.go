@@ -0,0 +1,243 @@
+// Package scheduler turns scrape requests into durable, retryable
+// background jobs instead of running them synchronously in the request.
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// Status is the lifecycle state of a scrape job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Job is a single scrape run, whether one-off or the latest occurrence of a
+// recurring schedule.
+type Job struct {
+	ID             string            `json:"id"`
+	Jurisdiction   string            `json:"jurisdiction"`
+	Type           string            `json:"type"`
+	Parameters     map[string]string `json:"parameters,omitempty"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+	CronSchedule   string            `json:"cron_schedule,omitempty"`
+
+	Status          Status `json:"status"`
+	Attempt         int    `json:"attempt"`
+	PagesFetched    int    `json:"pages_fetched"`
+	RecordsInserted int    `json:"records_inserted"`
+	ErrorCount      int    `json:"error_count"`
+	LastError       string `json:"last_error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists scrape jobs and a dead-letter table for jobs that
+// exhausted their retries.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore opens a Postgres connection at dsn and ensures the scheduler's
+// tables exist.
+func NewStore(dsn string) (*Store, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS scraper_jobs (
+	id               UUID PRIMARY KEY,
+	jurisdiction     TEXT NOT NULL,
+	type             TEXT NOT NULL,
+	parameters       JSONB NOT NULL DEFAULT '{}',
+	idempotency_key  TEXT UNIQUE,
+	cron_schedule    TEXT,
+	status           TEXT NOT NULL,
+	attempt          INT NOT NULL DEFAULT 0,
+	pages_fetched    INT NOT NULL DEFAULT 0,
+	records_inserted INT NOT NULL DEFAULT 0,
+	error_count      INT NOT NULL DEFAULT 0,
+	last_error       TEXT,
+	created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS scraper_dead_letters (
+	id           UUID PRIMARY KEY,
+	job_id       UUID NOT NULL,
+	jurisdiction TEXT NOT NULL,
+	type         TEXT NOT NULL,
+	last_error   TEXT,
+	attempts     INT NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating scheduler tables: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Insert writes a new job in StatusQueued. If idempotencyKey collides with
+// an existing job, the existing job is returned instead (at-least-once
+// request semantics without creating a duplicate run).
+func (s *Store) Insert(ctx context.Context, j *Job) (*Job, bool, error) {
+	params, err := marshalParams(j.Parameters)
+	if err != nil {
+		return nil, false, err
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO scraper_jobs (id, jurisdiction, type, parameters, idempotency_key, cron_schedule, status)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING id, created_at, updated_at`,
+		j.ID, j.Jurisdiction, j.Type, params, j.IdempotencyKey, j.CronSchedule, StatusQueued)
+
+	var id string
+	if err := row.Scan(&id, &j.CreatedAt, &j.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows && j.IdempotencyKey != "" {
+			existing, err := s.GetByIdempotencyKey(ctx, j.IdempotencyKey)
+			if err != nil {
+				return nil, false, err
+			}
+			return existing, true, nil
+		}
+		return nil, false, fmt.Errorf("inserting job: %w", err)
+	}
+	j.Status = StatusQueued
+	return j, false, nil
+}
+
+// GetByIdempotencyKey looks up a previously enqueued job by its caller-
+// supplied idempotency key.
+func (s *Store) GetByIdempotencyKey(ctx context.Context, key string) (*Job, error) {
+	return s.scanOne(s.db.QueryRowContext(ctx, jobSelect+` WHERE idempotency_key = $1`, key))
+}
+
+// Get looks up a job by ID.
+func (s *Store) Get(ctx context.Context, id string) (*Job, error) {
+	return s.scanOne(s.db.QueryRowContext(ctx, jobSelect+` WHERE id = $1`, id))
+}
+
+// List returns jobs, optionally filtered by status ("" means all).
+func (s *Store) List(ctx context.Context, status Status) ([]*Job, error) {
+	var rows *sql.Rows
+	var err error
+	if status == "" {
+		rows, err = s.db.QueryContext(ctx, jobSelect+` ORDER BY created_at DESC`)
+	} else {
+		rows, err = s.db.QueryContext(ctx, jobSelect+` WHERE status = $1 ORDER BY created_at DESC`, status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+// UpdateStatus transitions a job to status, optionally recording an error.
+func (s *Store) UpdateStatus(ctx context.Context, id string, status Status, lastErr string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE scraper_jobs SET status = $1, last_error = NULLIF($2, ''), updated_at = now() WHERE id = $3`,
+		status, lastErr, id)
+	return err
+}
+
+// UpdateProgress records the latest page/record/error counters for id.
+func (s *Store) UpdateProgress(ctx context.Context, id string, pages, records, errs int) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE scraper_jobs SET pages_fetched = $1, records_inserted = $2, error_count = $3, updated_at = now() WHERE id = $4`,
+		pages, records, errs, id)
+	return err
+}
+
+// IncrementAttempt bumps the retry counter, returning the new attempt count.
+func (s *Store) IncrementAttempt(ctx context.Context, id string) (int, error) {
+	var attempt int
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE scraper_jobs SET attempt = attempt + 1, updated_at = now() WHERE id = $1 RETURNING attempt`,
+		id).Scan(&attempt)
+	return attempt, err
+}
+
+// MoveToDeadLetter records a job that exhausted its retries.
+func (s *Store) MoveToDeadLetter(ctx context.Context, j *Job) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO scraper_dead_letters (id, job_id, jurisdiction, type, last_error, attempts)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.NewString(), j.ID, j.Jurisdiction, j.Type, j.LastError, j.Attempt)
+	return err
+}
+
+func marshalParams(params map[string]string) ([]byte, error) {
+	if params == nil {
+		params = map[string]string{}
+	}
+	return json.Marshal(params)
+}
+
+func unmarshalParams(raw []byte) map[string]string {
+	var params map[string]string
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return map[string]string{}
+	}
+	return params
+}
+
+const jobSelect = `SELECT id, jurisdiction, type, parameters, COALESCE(idempotency_key, ''), COALESCE(cron_schedule, ''),
+	status, attempt, pages_fetched, records_inserted, error_count, COALESCE(last_error, ''), created_at, updated_at
+	FROM scraper_jobs`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (s *Store) scanOne(row rowScanner) (*Job, error) {
+	return scanJob(row)
+}
+
+func scanJob(row rowScanner) (*Job, error) {
+	var j Job
+	var params []byte
+	if err := row.Scan(&j.ID, &j.Jurisdiction, &j.Type, &params, &j.IdempotencyKey, &j.CronSchedule,
+		&j.Status, &j.Attempt, &j.PagesFetched, &j.RecordsInserted, &j.ErrorCount, &j.LastError,
+		&j.CreatedAt, &j.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("scanning job: %w", err)
+	}
+	j.Parameters = unmarshalParams(params)
+	return &j, nil
+}
@@ -0,0 +1,145 @@
+// Package users provides Postgres-backed CRUD for admin accounts.
+package users
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User is an admin account. PasswordHash is never serialized to JSON.
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+
+	PasswordHash string `json:"-"`
+}
+
+// Store persists admin users in Postgres.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore opens a Postgres connection at dsn and ensures the admin_users
+// table exists.
+func NewStore(dsn string) (*Store, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS admin_users (
+	id            UUID PRIMARY KEY,
+	username      TEXT NOT NULL UNIQUE,
+	password_hash TEXT NOT NULL,
+	created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating admin_users table: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// List returns every admin user, ordered by username.
+func (s *Store) List(ctx context.Context) ([]User, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, username, password_hash, created_at FROM admin_users ORDER BY username`)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+	defer rows.Close()
+
+	var out []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning user: %w", err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// Create hashes password with bcrypt and inserts a new admin user.
+func (s *Store) Create(ctx context.Context, username, password string) (*User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("hashing password: %w", err)
+	}
+
+	u := &User{ID: uuid.NewString(), Username: username, PasswordHash: string(hash)}
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO admin_users (id, username, password_hash) VALUES ($1, $2, $3) RETURNING created_at`,
+		u.ID, u.Username, u.PasswordHash,
+	).Scan(&u.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("creating user: %w", err)
+	}
+	return u, nil
+}
+
+// UpdatePassword re-hashes and stores a new password for the given user ID.
+func (s *Store) UpdatePassword(ctx context.Context, id, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE admin_users SET password_hash = $1 WHERE id = $2`, string(hash), id)
+	if err != nil {
+		return fmt.Errorf("updating user %s: %w", id, err)
+	}
+	return requireRowAffected(res, id)
+}
+
+// Delete removes an admin user by ID.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM admin_users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deleting user %s: %w", id, err)
+	}
+	return requireRowAffected(res, id)
+}
+
+// Authenticate looks up username and compares password against its stored
+// hash, returning the user on success.
+func (s *Store) Authenticate(ctx context.Context, username, password string) (*User, error) {
+	var u User
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, username, password_hash, created_at FROM admin_users WHERE username = $1`, username,
+	).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("looking up user %q: %w", username, err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid credentials")
+	}
+	return &u, nil
+}
+
+func requireRowAffected(res sql.Result, id string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("user %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+// Package wshub is a WebSocket pub/sub hub: clients subscribe to a topic
+// (e.g. "votes" or "scrape:<job_id>") and receive every event published to
+// it. When Redis is configured, published events are also broadcast through
+// Redis pub/sub so multiple API instances stay in sync.
+package wshub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait is how long a write to a client may block before it's
+	// considered a slow/dead consumer.
+	writeWait = 10 * time.Second
+	// pongWait is how long to wait for a pong before the connection is
+	// considered dead.
+	pongWait = 60 * time.Second
+	// pingPeriod must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+	// clientSendBuffer is the per-client outbound buffer; once full, new
+	// events are dropped for that client rather than blocking the hub.
+	clientSendBuffer = 32
+)
+
+var redisChannelPrefix = "ws:"
+
+// Upgrader is the shared websocket.Upgrader for hub endpoints. Subprotocols
+// lists what the server is willing to speak; the first one the client also
+// offers is echoed back in the handshake response.
+var Upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	Subprotocols:    []string{"json.v1"},
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// Client is a single subscriber connection on one topic.
+type Client struct {
+	hub   *Hub
+	topic string
+	conn  *websocket.Conn
+	send  chan []byte
+
+	closeOnce sync.Once
+}
+
+// Hub fans out published events to every client subscribed to a topic.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[string]map[*Client]bool
+
+	redis       *redis.Client
+	redisCtx    context.Context
+	redisCancel context.CancelFunc
+}
+
+// NewHub returns a Hub. If redisAddr is non-empty, published events are also
+// relayed through Redis pub/sub so other API instances see them.
+func NewHub(redisAddr string) *Hub {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Hub{
+		clients:     map[string]map[*Client]bool{},
+		redisCtx:    ctx,
+		redisCancel: cancel,
+	}
+	if redisAddr != "" {
+		h.redis = redis.NewClient(&redis.Options{Addr: redisAddr})
+		go h.relayFromRedis()
+	}
+	return h
+}
+
+// relayFromRedis subscribes to every ws:* channel and fans incoming
+// messages into the matching local topic's clients.
+func (h *Hub) relayFromRedis() {
+	sub := h.redis.PSubscribe(h.redisCtx, redisChannelPrefix+"*")
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-h.redisCtx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			topic := msg.Channel[len(redisChannelPrefix):]
+			h.broadcastLocal(topic, []byte(msg.Payload))
+		}
+	}
+}
+
+// Subscribe registers conn as a client of topic and starts its read/write
+// pumps. It takes ownership of conn's lifecycle.
+func (h *Hub) Subscribe(topic string, conn *websocket.Conn) *Client {
+	c := &Client{hub: h, topic: topic, conn: conn, send: make(chan []byte, clientSendBuffer)}
+
+	h.mu.Lock()
+	if h.clients[topic] == nil {
+		h.clients[topic] = map[*Client]bool{}
+	}
+	h.clients[topic][c] = true
+	h.mu.Unlock()
+
+	go c.writePump()
+	go c.readPump()
+	return c
+}
+
+func (h *Hub) unsubscribe(c *Client) {
+	h.mu.Lock()
+	if clients, ok := h.clients[c.topic]; ok {
+		delete(clients, c)
+		if len(clients) == 0 {
+			delete(h.clients, c.topic)
+		}
+	}
+	h.mu.Unlock()
+}
+
+// Publish sends event (marshalled as JSON) to every subscriber of topic,
+// locally and (if configured) via Redis so other instances' subscribers
+// receive it too.
+func (h *Hub) Publish(ctx context.Context, topic string, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshalling event: %w", err)
+	}
+
+	if h.redis != nil {
+		if err := h.redis.Publish(ctx, redisChannelPrefix+topic, data).Err(); err != nil {
+			return fmt.Errorf("publishing to redis: %w", err)
+		}
+		return nil
+	}
+
+	h.broadcastLocal(topic, data)
+	return nil
+}
+
+func (h *Hub) broadcastLocal(topic string, data []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.clients[topic] {
+		select {
+		case c.send <- data:
+		default:
+			// Slow consumer: drop this event rather than block the hub.
+		}
+	}
+}
+
+// Shutdown closes every client connection with a 1001 "going away" close
+// frame and stops the Redis relay.
+func (h *Hub) Shutdown(ctx context.Context) {
+	h.mu.RLock()
+	var all []*Client
+	for _, clients := range h.clients {
+		for c := range clients {
+			all = append(all, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range all {
+		c.close(websocket.CloseGoingAway, "server shutting down")
+	}
+	if h.redis != nil {
+		h.redisCancel()
+		h.redis.Close()
+	}
+}
+
+func (c *Client) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		deadline := time.Now().Add(writeWait)
+		msg := websocket.FormatCloseMessage(code, reason)
+		c.conn.WriteControl(websocket.CloseMessage, msg, deadline)
+		c.conn.Close()
+	})
+}
+
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.unsubscribe(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close(websocket.CloseNormalClosure, "")
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}